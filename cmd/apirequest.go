@@ -0,0 +1,155 @@
+/*
+Copyright © 2020 Hover Developer Services <support@usehover.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// idempotentMethods are the methods APIRequest will retry on a network
+// error or 5xx response; POST/PATCH are left alone since replaying them
+// could duplicate a write.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodHead:   true,
+	http.MethodPut:    true,
+	http.MethodDelete: true,
+}
+
+// RetryPolicy controls how APIRequest retries idempotent requests. Sleep,
+// Now and Reauthenticate are exposed so tests can inject a fake clock and
+// a fake reauth round-trip instead of actually waiting out the backoff or
+// hitting a real /authenticate endpoint.
+type RetryPolicy struct {
+	MaxRetries     int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	Sleep          func(time.Duration)
+	Now            func() time.Time
+	Reauthenticate func() (*Config, error)
+}
+
+// defaultRetryPolicy reads MaxRetries from config and uses real time.
+func defaultRetryPolicy(cfg *Config) RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     cfg.MaxRetries,
+		BaseDelay:      200 * time.Millisecond,
+		MaxDelay:       10 * time.Second,
+		Sleep:          time.Sleep,
+		Now:            time.Now,
+		Reauthenticate: reauthenticate,
+	}
+}
+
+// reauthenticate forces a fresh auth token via authenticate(true) and
+// resolves the config that reflects it, for RetryPolicy.Reauthenticate.
+func reauthenticate() (*Config, error) {
+	if err := authenticate(true); err != nil {
+		return nil, err
+	}
+	return resolveConfig()
+}
+
+// backoff returns the exponential delay for a given attempt (0-indexed),
+// capped at MaxDelay and with up to 50% jitter so a fleet of clients
+// retrying the same failure don't all hammer the API in lockstep.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay * time.Duration(uint64(1)<<uint(attempt))
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay/2 + jitter
+}
+
+// APIRequest makes a request to the Hover API. GET/HEAD/PUT/DELETE are
+// retried with exponential backoff on network errors and 5xx responses; a
+// 429 is retried once its Retry-After has elapsed; a 401 triggers a single
+// forced Authenticate + replay, since the stored auth_token may have been
+// revoked server-side before its local expiry.
+func APIRequest(method string, endpoint string, payload []byte) (*http.Response, error) {
+	cfg, err := resolveConfig()
+	if err != nil {
+		return nil, err
+	}
+	return apiRequest(cfg, defaultRetryPolicy(cfg), method, endpoint, payload, true)
+}
+
+func apiRequest(cfg *Config, policy RetryPolicy, method, endpoint string, payload []byte, allowReauth bool) (*http.Response, error) {
+	client := &http.Client{Timeout: cfg.Timeout}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := doRequest(client, cfg, method, endpoint, payload)
+
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && allowReauth {
+			resp.Body.Close()
+			refreshed, reauthErr := policy.Reauthenticate()
+			if reauthErr != nil {
+				return nil, reauthErr
+			}
+			return apiRequest(refreshed, policy, method, endpoint, payload, false)
+		}
+
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests && attempt < policy.MaxRetries {
+			wait := retryAfter(resp, policy)
+			resp.Body.Close()
+			policy.Sleep(wait)
+			continue
+		}
+
+		retriable := idempotentMethods[method] && (err != nil || resp.StatusCode >= 500)
+		if !retriable || attempt >= policy.MaxRetries {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		policy.Sleep(policy.backoff(attempt))
+	}
+}
+
+func doRequest(client *http.Client, cfg *Config, method, endpoint string, payload []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, cfg.APIURL+endpoint, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", cfg.AuthToken)
+	LogDebugf("%s %s", method, req.URL)
+	return client.Do(req)
+}
+
+// retryAfter honors a 429's Retry-After header, falling back to the
+// policy's own backoff when the header is absent or unparseable.
+func retryAfter(resp *http.Response, policy RetryPolicy) time.Duration {
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return policy.backoff(0)
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		if wait := when.Sub(policy.Now()); wait > 0 {
+			return wait
+		}
+	}
+	return policy.backoff(0)
+}
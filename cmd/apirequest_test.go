@@ -0,0 +1,218 @@
+/*
+Copyright © 2020 Hover Developer Services <support@usehover.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// noSleepPolicy's Reauthenticate fails loudly if a test exercises the 401
+// path without stubbing it - tests that care about reauth set their own.
+func noSleepPolicy(maxRetries int) RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: maxRetries,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   time.Millisecond,
+		Sleep:      func(time.Duration) {},
+		Now:        time.Now,
+		Reauthenticate: func() (*Config, error) {
+			return nil, errors.New("Reauthenticate not stubbed for this test")
+		},
+	}
+}
+
+func TestAPIRequestRetriesIdempotentMethods(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		statuses   []int
+		maxRetries int
+		wantCalls  int
+		wantStatus int
+	}{
+		{
+			name:       "succeeds first try",
+			method:     http.MethodGet,
+			statuses:   []int{http.StatusOK},
+			maxRetries: 3,
+			wantCalls:  1,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "retries on 5xx then succeeds",
+			method:     http.MethodGet,
+			statuses:   []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries: 3,
+			wantCalls:  3,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "gives up after max retries",
+			method:     http.MethodGet,
+			statuses:   []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			maxRetries: 1,
+			wantCalls:  2,
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "does not retry non-idempotent methods",
+			method:     http.MethodPost,
+			statuses:   []int{http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries: 3,
+			wantCalls:  1,
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				idx := calls
+				if idx >= len(tt.statuses) {
+					idx = len(tt.statuses) - 1
+				}
+				calls++
+				w.WriteHeader(tt.statuses[idx])
+			}))
+			defer server.Close()
+
+			cfg := &Config{APIURL: server.URL + "/", Timeout: time.Second}
+			resp, err := apiRequest(cfg, noSleepPolicy(tt.maxRetries), tt.method, "things", nil, false)
+			if err != nil {
+				t.Fatalf("apiRequest returned error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestAPIRequestForcesReauthOn401(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			if got := r.Header.Get("Authorization"); got != "stale-token" {
+				t.Errorf("first call Authorization = %q, want stale-token", got)
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "refreshed-token" {
+			t.Errorf("replay Authorization = %q, want refreshed-token", got)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &Config{APIURL: server.URL + "/", Timeout: time.Second, AuthToken: "stale-token"}
+	reauthCalls := 0
+	policy := noSleepPolicy(3)
+	policy.Reauthenticate = func() (*Config, error) {
+		reauthCalls++
+		refreshed := *cfg
+		refreshed.AuthToken = "refreshed-token"
+		return &refreshed, nil
+	}
+
+	resp, err := apiRequest(cfg, policy, http.MethodGet, "things", nil, true)
+	if err != nil {
+		t.Fatalf("apiRequest returned error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 (original 401 + replay)", calls)
+	}
+	if reauthCalls != 1 {
+		t.Errorf("reauthCalls = %d, want 1", reauthCalls)
+	}
+}
+
+func TestAPIRequestDoesNotReauthOnReplayedRequest(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	cfg := &Config{APIURL: server.URL + "/", Timeout: time.Second}
+	reauthCalls := 0
+	policy := noSleepPolicy(3)
+	policy.Reauthenticate = func() (*Config, error) {
+		reauthCalls++
+		return cfg, nil
+	}
+
+	resp, err := apiRequest(cfg, policy, http.MethodGet, "things", nil, true)
+	if err != nil {
+		t.Fatalf("apiRequest returned error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", resp.StatusCode)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 (original + one replay, no reauth loop)", calls)
+	}
+	if reauthCalls != 1 {
+		t.Errorf("reauthCalls = %d, want 1 (reauth only happens once, not per replay)", reauthCalls)
+	}
+}
+
+func TestAPIRequestHonorsRetryAfter(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &Config{APIURL: server.URL + "/", Timeout: time.Second}
+	resp, err := apiRequest(cfg, noSleepPolicy(1), http.MethodGet, "things", nil, false)
+	if err != nil {
+		t.Fatalf("apiRequest returned error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2020 Hover Developer Services <support@usehover.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// defaultAPIURL is used when no api_url is set via flag, env or config file.
+const defaultAPIURL = "http://localhost:3000/api/"
+
+// Config holds the settings Authenticate and APIRequest act on. It is
+// populated from (in increasing priority) the profiles.<profile> sub-tree,
+// top-level config file keys, HOVER_-prefixed environment variables and
+// command-line flags.
+type Config struct {
+	APIURL   string        `mapstructure:"api_url"`
+	Email    string        `mapstructure:"email"`
+	Password string        `mapstructure:"password"`
+	Profile  string        `mapstructure:"profile"`
+	Timeout  time.Duration `mapstructure:"http_timeout"`
+
+	MaxRetries int `mapstructure:"max_retries"`
+
+	AuthToken       string    `mapstructure:"auth_token"`
+	AuthTokenExpiry time.Time `mapstructure:"auth_token_expiry"`
+}
+
+// resolveConfig builds a Config from the global viper instance plus the
+// machine-managed token cache. Flags, HOVER_-prefixed environment
+// variables and top-level config file keys are read first; a field is
+// only then filled in from the profiles.<name> sub-tree if none of those
+// explicitly set it, so `--profile staging` supplies `profiles.staging.api_url`
+// and friends without a profile ever overriding an explicit `--api-url`
+// (including one that happens to equal the default). auth_token/
+// auth_token_expiry never live in the credentials file — they're read
+// from the token cache, see token.go.
+func resolveConfig() (*Config, error) {
+	cfg := &Config{
+		APIURL:     defaultAPIURL,
+		Timeout:    30 * time.Second,
+		MaxRetries: 3,
+	}
+	if err := viper.Unmarshal(cfg); err != nil {
+		return nil, err
+	}
+
+	profile := viper.GetString("profile")
+	if profile != "" {
+		sub := viper.Sub("profiles." + profile)
+		if sub == nil {
+			return nil, fmt.Errorf("no such profile: %s", profile)
+		}
+		var profileCfg Config
+		if err := sub.Unmarshal(&profileCfg); err != nil {
+			return nil, err
+		}
+
+		if !explicitlySet("api-url", "api_url") && profileCfg.APIURL != "" {
+			cfg.APIURL = profileCfg.APIURL
+		}
+		if !explicitlySet("email", "email") {
+			cfg.Email = profileCfg.Email
+		}
+		if !explicitlySet("password", "password") {
+			cfg.Password = profileCfg.Password
+		}
+	}
+
+	tokens, err := readTokenCache()
+	if err != nil {
+		return nil, err
+	}
+	cfg.AuthToken = tokens.AuthToken
+	cfg.AuthTokenExpiry = tokens.AuthTokenExpiry
+
+	return cfg, nil
+}
+
+// explicitlySet reports whether flagName was passed on the command line,
+// its HOVER_<key> environment variable is set, or key is present in the
+// top-level config file - any of which should win over a profile's value,
+// even one that happens to match the flag's zero/default value.
+func explicitlySet(flagName, key string) bool {
+	if f := rootCmd.PersistentFlags().Lookup(flagName); f != nil && f.Changed {
+		return true
+	}
+	if _, ok := os.LookupEnv("HOVER_" + strings.ToUpper(key)); ok {
+		return true
+	}
+	return viper.InConfig(key)
+}
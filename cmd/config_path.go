@@ -0,0 +1,82 @@
+/*
+Copyright © 2020 Hover Developer Services <support@usehover.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// configSearchOrder lists the credentials file locations hovercli checks,
+// in priority order, when --config isn't given. The base file name
+// differs between the XDG-style paths and the legacy default, which is
+// why this is a plain ordered list of full paths rather than a single
+// viper.SetConfigName + stacked AddConfigPath calls.
+func configSearchOrder() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		paths = append(paths, filepath.Join(xdg, "hovercli", "config.yaml"))
+	}
+	paths = append(paths,
+		filepath.Join(home, ".config", "hovercli", "config.yaml"),
+		filepath.Join(home, ".hovercli.yaml"),
+	)
+	return paths
+}
+
+// findConfigFile returns the first path in configSearchOrder that exists,
+// or "" if none do.
+func findConfigFile() string {
+	for _, path := range configSearchOrder() {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect hovercli's configuration",
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the config file hovercli resolved and will read",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := viper.ConfigFileUsed()
+		if path == "" {
+			return fmt.Errorf("no config file found; searched %s", strings.Join(configSearchOrder(), ", "))
+		}
+		fmt.Println(path)
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configPathCmd)
+	rootCmd.AddCommand(configCmd)
+}
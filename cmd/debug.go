@@ -0,0 +1,32 @@
+/*
+Copyright © 2020 Hover Developer Services <support@usehover.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/viper"
+)
+
+// LogDebugf logs format/args only when --debug (or HOVER_DEBUG) is set.
+// Generated commands use it to print the outgoing request and the
+// response they get back, without cluttering normal output.
+func LogDebugf(format string, args ...interface{}) {
+	if !viper.GetBool("debug") {
+		return
+	}
+	log.Printf(format, args...)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2020 Hover Developer Services <support@usehover.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/HenryKenya/hovercli/cmd/generate"
+)
+
+// generateOutDir is where generated commands and types are written.
+// Everything under it is machine-maintained; re-running `generate`
+// overwrites it wholesale.
+const generateOutDir = "cmd/generated"
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate action/API commands from the Hover OpenAPI spec",
+	Long: `generate reads the Hover API's OpenAPI/Swagger document and emits a
+typed cobra subcommand plus request/response types for every operation it
+finds, under cmd/generated. Run it again whenever the spec changes instead
+of hand-maintaining ActionRequest/ActionResponse-style scaffolding.`,
+	RunE: runGenerate,
+}
+
+func init() {
+	generateCmd.Flags().String("spec-file", "", "path or URL of the OpenAPI document (defaults to spec_file in config)")
+	if err := viper.BindPFlag("spec_file", generateCmd.Flags().Lookup("spec-file")); err != nil {
+		fmt.Println(err)
+	}
+
+	rootCmd.AddCommand(generateCmd)
+}
+
+func runGenerate(cmd *cobra.Command, args []string) error {
+	specFile := viper.GetString("spec_file")
+	if specFile == "" {
+		return fmt.Errorf("no spec file configured; set spec_file in your config or pass --spec-file")
+	}
+
+	doc, err := generate.LoadSpec(specFile)
+	if err != nil {
+		return err
+	}
+
+	ops, err := generate.Walk(doc)
+	if err != nil {
+		return err
+	}
+
+	if err := generate.Write(generateOutDir, ops); err != nil {
+		return err
+	}
+
+	fmt.Printf("generated %d command(s) into %s\n", len(ops), generateOutDir)
+	return nil
+}
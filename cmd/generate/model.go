@@ -0,0 +1,285 @@
+/*
+Copyright © 2020 Hover Developer Services <support@usehover.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package generate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// maxDepth bounds how deeply a nested model (body parameter, or a model
+// field that itself references another model) is flattened into flags.
+// Specs with self-referencing or very deep models would otherwise make
+// Walk recurse forever.
+const maxDepth = 5
+
+// Flag describes one pflag generated for an operation, either from a
+// path/query/header parameter or from flattening a JSON body model.
+type Flag struct {
+	Name     string // pflag name, e.g. "world-operator-ids"
+	GoField  string // request struct field name, e.g. "WorldOperatorIds"
+	GoType   string // "string", "int64", "bool", "float64", "[]string"
+	Required bool
+	Usage    string
+}
+
+// FlagKind returns the pflag accessor suffix matching GoType, e.g.
+// flags.Int64/flags.GetInt64 for "int64". Used by the operation template
+// so generated flags and struct fields actually carry the OpenAPI type
+// instead of flattening everything to a string.
+func (f Flag) FlagKind() string {
+	switch f.GoType {
+	case "int64":
+		return "Int64"
+	case "float64":
+		return "Float64"
+	case "bool":
+		return "Bool"
+	case "[]string":
+		return "StringSlice"
+	default:
+		return "String"
+	}
+}
+
+// FlagZero returns the Go literal used as the flag's default value.
+func (f Flag) FlagZero() string {
+	switch f.GoType {
+	case "int64":
+		return "0"
+	case "float64":
+		return "0"
+	case "bool":
+		return "false"
+	case "[]string":
+		return "nil"
+	default:
+		return `""`
+	}
+}
+
+// Operation is the generation-time view of a single OpenAPI operation:
+// everything the command/type templates need.
+type Operation struct {
+	ID         string // used for command Use and generated type names
+	Method     string
+	Path       string
+	Summary    string
+	CommandVar string // Go identifier for the generated *cobra.Command
+	TypeName   string // Go identifier for the generated request body type
+	Flags      []Flag
+}
+
+// Walk flattens every operation in doc into the data Write (render.go)
+// needs, skipping operations with no operationId since we have no stable
+// name to hang a command or type off of.
+func Walk(doc *spec.Swagger) ([]Operation, error) {
+	if doc.Paths == nil {
+		return nil, nil
+	}
+
+	paths := make([]string, 0, len(doc.Paths.Paths))
+	for p := range doc.Paths.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var ops []Operation
+	for _, p := range paths {
+		item := doc.Paths.Paths[p]
+		for _, method := range methodOrder(item) {
+			op := operationsForPathItem(item)[method]
+			if op.ID == "" {
+				continue
+			}
+
+			flags, err := flagsForOperation(op)
+			if err != nil {
+				return nil, fmt.Errorf("operation %s %s (%s): %w", method, p, op.ID, err)
+			}
+
+			ops = append(ops, Operation{
+				ID:         op.ID,
+				Method:     method,
+				Path:       p,
+				Summary:    op.Summary,
+				CommandVar: titleCase(op.ID) + "Cmd",
+				TypeName:   titleCase(op.ID) + "Request",
+				Flags:      flags,
+			})
+		}
+	}
+
+	return ops, nil
+}
+
+func flagsForOperation(op *spec.Operation) ([]Flag, error) {
+	var paramFlags []Flag
+	var bodyFlags []Flag
+	for _, param := range op.Parameters {
+		if param.In == "body" && param.Schema != nil {
+			nested, err := flagsForSchema(param.Schema, "", 0)
+			if err != nil {
+				return nil, err
+			}
+			bodyFlags = append(bodyFlags, nested...)
+			continue
+		}
+
+		paramFlags = append(paramFlags, Flag{
+			Name:     param.Name,
+			GoField:  titleCase(toGoName(param.Name)),
+			GoType:   goTypeForParam(param),
+			Required: param.Required,
+			Usage:    param.Description,
+		})
+	}
+	return mergeFlags(paramFlags, bodyFlags), nil
+}
+
+// mergeFlags combines a path/query parameter's flags with a flattened
+// body's flags. Parameter names are already unique within an operation
+// (the spec guarantees it) and flagsForSchema already disambiguates
+// flags within the body by flattened path, but a body field can still
+// collide with a parameter of the same name (e.g. a path param "id" and
+// a top-level body property "id"). Any body flag that collides with a
+// flag already claimed by a parameter or an earlier body flag is
+// re-qualified under "body" prefixes until it is free, so every Name and
+// GoField in the result is unique.
+func mergeFlags(paramFlags, bodyFlags []Flag) []Flag {
+	flags := append([]Flag{}, paramFlags...)
+	names := make(map[string]bool, len(flags))
+	fields := make(map[string]bool, len(flags))
+	for _, f := range flags {
+		names[f.Name] = true
+		fields[f.GoField] = true
+	}
+
+	for _, f := range bodyFlags {
+		for names[f.Name] || fields[f.GoField] {
+			f.Name = toFlagName("body-" + f.Name)
+			f.GoField = titleCase(toGoName("body-" + f.GoField))
+		}
+		names[f.Name] = true
+		fields[f.GoField] = true
+		flags = append(flags, f)
+	}
+	return flags
+}
+
+// flagsForSchema flattens an object schema's properties into flags. The
+// request type stays a single flat struct, so nested object properties
+// contribute a dashed flag name built from the full property path (e.g.
+// "custom-action-name"); the Go field name is derived from that same
+// full path rather than just the leaf property, so two properties that
+// happen to share a leaf name at different nesting depths (e.g. "id" and
+// "owner.id") don't collide on either the flag or the struct field. It
+// stops descending once depth reaches maxDepth so recursive or
+// pathological models can't produce an unbounded number of flags.
+func flagsForSchema(schema *spec.Schema, namePrefix string, depth int) ([]Flag, error) {
+	if depth >= maxDepth {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var flags []Flag
+	for _, name := range names {
+		prop := schema.Properties[name]
+		flagName := name
+		if namePrefix != "" {
+			flagName = namePrefix + "-" + name
+		}
+
+		if prop.Type.Contains("object") && len(prop.Properties) > 0 {
+			nested, err := flagsForSchema(&prop, flagName, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			flags = append(flags, nested...)
+			continue
+		}
+
+		flags = append(flags, Flag{
+			Name:     toFlagName(flagName),
+			GoField:  titleCase(toGoName(flagName)),
+			GoType:   goTypeForSchema(&prop),
+			Required: contains(schema.Required, name),
+			Usage:    prop.Description,
+		})
+	}
+	return flags, nil
+}
+
+func goTypeForParam(param spec.Parameter) string {
+	switch param.Type {
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]string"
+	default:
+		return "string"
+	}
+}
+
+func goTypeForSchema(schema *spec.Schema) string {
+	switch {
+	case schema.Type.Contains("integer"):
+		return "int64"
+	case schema.Type.Contains("number"):
+		return "float64"
+	case schema.Type.Contains("boolean"):
+		return "bool"
+	case schema.Type.Contains("array"):
+		return "[]string"
+	default:
+		return "string"
+	}
+}
+
+func toGoName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-'
+	})
+	for i, p := range parts {
+		parts[i] = titleCase(p)
+	}
+	return strings.Join(parts, "")
+}
+
+func toFlagName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
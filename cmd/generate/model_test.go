@@ -0,0 +1,257 @@
+/*
+Copyright © 2020 Hover Developer Services <support@usehover.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package generate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+)
+
+func mustSwagger(t *testing.T, doc string) *spec.Swagger {
+	t.Helper()
+	var s spec.Swagger
+	if err := json.Unmarshal([]byte(doc), &s); err != nil {
+		t.Fatalf("unmarshalling test spec: %v", err)
+	}
+	return &s
+}
+
+func flagByName(flags []Flag, name string) (Flag, bool) {
+	for _, f := range flags {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Flag{}, false
+}
+
+func TestWalkInfersGoTypesFromSchema(t *testing.T) {
+	doc := mustSwagger(t, `{
+		"swagger": "2.0",
+		"info": {"title": "t", "version": "1"},
+		"paths": {
+			"/widgets": {
+				"post": {
+					"operationId": "createWidget",
+					"parameters": [{
+						"name": "body",
+						"in": "body",
+						"schema": {
+							"type": "object",
+							"required": ["name"],
+							"properties": {
+								"name":   {"type": "string"},
+								"count":  {"type": "integer"},
+								"active": {"type": "boolean"},
+								"weight": {"type": "number"}
+							}
+						}
+					}]
+				}
+			}
+		}
+	}`)
+
+	ops, err := Walk(doc)
+	if err != nil {
+		t.Fatalf("Walk returned error: %v", err)
+	}
+	if len(ops) != 1 {
+		t.Fatalf("got %d operations, want 1", len(ops))
+	}
+
+	op := ops[0]
+	if op.ID != "createWidget" {
+		t.Errorf("ID = %q, want createWidget", op.ID)
+	}
+
+	cases := []struct {
+		name     string
+		wantType string
+		wantReq  bool
+	}{
+		{"name", "string", true},
+		{"count", "int64", false},
+		{"active", "bool", false},
+		{"weight", "float64", false},
+	}
+	for _, c := range cases {
+		flag, ok := flagByName(op.Flags, c.name)
+		if !ok {
+			t.Errorf("no flag named %q", c.name)
+			continue
+		}
+		if flag.GoType != c.wantType {
+			t.Errorf("flag %q: GoType = %q, want %q", c.name, flag.GoType, c.wantType)
+		}
+		if flag.Required != c.wantReq {
+			t.Errorf("flag %q: Required = %v, want %v", c.name, flag.Required, c.wantReq)
+		}
+	}
+}
+
+func TestWalkSkipsOperationsWithoutID(t *testing.T) {
+	doc := mustSwagger(t, `{
+		"swagger": "2.0",
+		"info": {"title": "t", "version": "1"},
+		"paths": {
+			"/widgets": {
+				"get": {}
+			}
+		}
+	}`)
+
+	ops, err := Walk(doc)
+	if err != nil {
+		t.Fatalf("Walk returned error: %v", err)
+	}
+	if len(ops) != 0 {
+		t.Fatalf("got %d operations, want 0", len(ops))
+	}
+}
+
+// nestedSchema builds a schema depth+1 levels deep: each level has a "leaf"
+// string property alongside a "nested" object property holding the next
+// level, so flagsForSchema emits one leaf flag per depth level walked.
+func nestedSchema(depth int) spec.Schema {
+	leaf := spec.Schema{}
+	leaf.Typed("string", "")
+
+	s := spec.Schema{}
+	s.Typed("object", "")
+	if depth <= 0 {
+		s.Properties = map[string]spec.Schema{"leaf": leaf}
+		return s
+	}
+
+	s.Properties = map[string]spec.Schema{
+		"leaf":   leaf,
+		"nested": nestedSchema(depth - 1),
+	}
+	return s
+}
+
+func TestFlagsForSchemaStopsAtMaxDepth(t *testing.T) {
+	schema := nestedSchema(maxDepth + 3)
+
+	flags, err := flagsForSchema(&schema, "", 0)
+	if err != nil {
+		t.Fatalf("flagsForSchema returned error: %v", err)
+	}
+
+	if len(flags) != maxDepth {
+		t.Fatalf("got %d flags, want %d (one leaf per depth level before maxDepth stops recursion)", len(flags), maxDepth)
+	}
+	for _, f := range flags {
+		if f.GoType != "string" {
+			t.Errorf("flag %s: GoType = %q, want string", f.Name, f.GoType)
+		}
+	}
+}
+
+func TestWalkUniquesCollidingNamesAndFields(t *testing.T) {
+	doc := mustSwagger(t, `{
+		"swagger": "2.0",
+		"info": {"title": "t", "version": "1"},
+		"paths": {
+			"/widgets/{id}": {
+				"get": {
+					"operationId": "getWidget",
+					"parameters": [
+						{"name": "id", "in": "path", "required": true, "type": "string"},
+						{
+							"name": "body",
+							"in": "body",
+							"schema": {
+								"type": "object",
+								"properties": {
+									"id": {"type": "string"},
+									"owner": {
+										"type": "object",
+										"properties": {
+											"id": {"type": "string"}
+										}
+									}
+								}
+							}
+						}
+					]
+				}
+			}
+		}
+	}`)
+
+	ops, err := Walk(doc)
+	if err != nil {
+		t.Fatalf("Walk returned error: %v", err)
+	}
+	if len(ops) != 1 {
+		t.Fatalf("got %d operations, want 1", len(ops))
+	}
+
+	flags := ops[0].Flags
+	names := make(map[string]bool, len(flags))
+	fields := make(map[string]bool, len(flags))
+	for _, f := range flags {
+		if names[f.Name] {
+			t.Errorf("duplicate flag Name %q", f.Name)
+		}
+		names[f.Name] = true
+		if fields[f.GoField] {
+			t.Errorf("duplicate flag GoField %q", f.GoField)
+		}
+		fields[f.GoField] = true
+	}
+
+	// The path param keeps the bare name; the colliding top-level body
+	// property gets qualified, and the nested owner.id was never
+	// ambiguous in the first place since its flattened path already
+	// differs from both.
+	if _, ok := flagByName(flags, "id"); !ok {
+		t.Errorf("expected a flag named \"id\" for the path param, got %+v", flags)
+	}
+	if _, ok := flagByName(flags, "body-id"); !ok {
+		t.Errorf("expected the colliding body property to be qualified as \"body-id\", got %+v", flags)
+	}
+	if _, ok := flagByName(flags, "owner-id"); !ok {
+		t.Errorf("expected a flag named \"owner-id\", got %+v", flags)
+	}
+}
+
+func TestFlagKindAndZero(t *testing.T) {
+	cases := []struct {
+		goType   string
+		wantKind string
+		wantZero string
+	}{
+		{"string", "String", `""`},
+		{"int64", "Int64", "0"},
+		{"float64", "Float64", "0"},
+		{"bool", "Bool", "false"},
+		{"[]string", "StringSlice", "nil"},
+	}
+	for _, c := range cases {
+		f := Flag{GoType: c.goType}
+		if got := f.FlagKind(); got != c.wantKind {
+			t.Errorf("FlagKind(%q) = %q, want %q", c.goType, got, c.wantKind)
+		}
+		if got := f.FlagZero(); got != c.wantZero {
+			t.Errorf("FlagZero(%q) = %q, want %q", c.goType, got, c.wantZero)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2020 Hover Developer Services <support@usehover.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package generate walks the Hover OpenAPI document and emits typed cobra
+// subcommands under cmd/generated, modeled loosely on go-swagger's CLI
+// template. It is the engine behind `hovercli generate`.
+package generate
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/spec"
+)
+
+// LoadSpec reads an OpenAPI/Swagger document from a local file path or a
+// URL (anything loads.Spec recognises as such) and returns its expanded
+// form, with all $refs resolved.
+func LoadSpec(location string) (*spec.Swagger, error) {
+	if location == "" {
+		return nil, fmt.Errorf("spec location is empty")
+	}
+
+	// loads.Spec already handles both local paths and http(s) URLs.
+	doc, err := loads.Spec(location)
+	if err != nil {
+		return nil, fmt.Errorf("loading spec %q: %w", location, err)
+	}
+
+	doc, err = doc.Expanded()
+	if err != nil {
+		return nil, fmt.Errorf("expanding spec %q: %w", location, err)
+	}
+
+	return doc.Spec(), nil
+}
+
+// supportedMethods lists the HTTP methods walked out of each path item, in
+// a stable order so generated output doesn't reshuffle between runs.
+var supportedMethods = []string{
+	http.MethodGet, http.MethodPut, http.MethodPost,
+	http.MethodDelete, http.MethodOptions, http.MethodHead, http.MethodPatch,
+}
+
+func operationsForPathItem(item spec.PathItem) map[string]*spec.Operation {
+	ops := map[string]*spec.Operation{
+		http.MethodGet:     item.Get,
+		http.MethodPut:     item.Put,
+		http.MethodPost:    item.Post,
+		http.MethodDelete:  item.Delete,
+		http.MethodOptions: item.Options,
+		http.MethodHead:    item.Head,
+		http.MethodPatch:   item.Patch,
+	}
+	for method, op := range ops {
+		if op == nil {
+			delete(ops, method)
+		}
+	}
+	return ops
+}
+
+// methodOrder returns the methods present on item in supportedMethods order.
+func methodOrder(item spec.PathItem) []string {
+	present := operationsForPathItem(item)
+	ordered := make([]string, 0, len(present))
+	for _, m := range supportedMethods {
+		if _, ok := present[m]; ok {
+			ordered = append(ordered, m)
+		}
+	}
+	return ordered
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
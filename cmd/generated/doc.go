@@ -0,0 +1,22 @@
+/*
+Copyright © 2020 Hover Developer Services <support@usehover.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package generated holds the cobra subcommands and request types built
+// from the Hover OpenAPI document by `hovercli generate`. Every file in
+// this package, this one included, is replaced on each run; run
+// `hovercli generate` after cloning, or whenever the spec changes, to
+// populate it.
+package generated
@@ -18,61 +18,37 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"path/filepath"
 	"time"
 
 	"os"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
 
-	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
 )
 
-// URL points to the Hover API url
-const URL = "http://localhost:3000/api/"
-
 var cfgFile string
 
-// ActionRequest is the request object when
-// creating actions
-type ActionRequest struct {
-	CustomAction ActionDetails `json:"custom_action"`
-}
-
-// ActionDetails contains the Action fields
-// used in ActionRequest
-type ActionDetails struct {
-	Name           string   `json:"name,omitempty"`
-	RootCode       string   `json:"root_code,omitempty"`
-	TransportType  string   `json:"transport_type,omitempty"`
-	WorldOperators []string `json:"world_operator_ids,omitempty"`
-}
-
-// Action struct represents an Action object
-type Action struct {
-	ID         string                 `json:"id"`
-	Attributes map[string]interface{} `json:"attributes"`
-}
-
-// ActionListResponse struct represents a response containing
-// a list of actions
-type ActionListResponse struct {
-	Data []Action
-}
-
-// ActionResponse struct represents an action response
-type ActionResponse struct {
-	Data Action
-}
-
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "hovercli",
 	Short: "Welcome to the Hover Command Line Interface.",
-	Long:  `Welcome to the Hover Command Line Interface.`,
+	Long: `Welcome to the Hover Command Line Interface.
+
+Without --config, hovercli searches for its credentials file in this
+order and reads the first one it finds:
+
+  1. $XDG_CONFIG_HOME/hovercli/config.yaml
+  2. $HOME/.config/hovercli/config.yaml
+  3. $HOME/.hovercli.yaml (legacy default)
+
+Run "hovercli config path" to see which one it resolved to.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	//	Run: func(cmd *cobra.Command, args []string) { },
@@ -87,6 +63,13 @@ func Execute() {
 	}
 }
 
+// RootCmd returns the base hovercli command so generated subcommands
+// (cmd/generated) can register themselves without creating an import
+// cycle back through this package.
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
@@ -94,7 +77,32 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.hovercli.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: see \"hovercli config path\")")
+	rootCmd.PersistentFlags().String("api-url", defaultAPIURL, "Hover API base URL")
+	rootCmd.PersistentFlags().String("email", "", "Hover account email")
+	rootCmd.PersistentFlags().String("password", "", "Hover account password")
+	rootCmd.PersistentFlags().String("profile", "", "named credentials profile to use, e.g. staging")
+	rootCmd.PersistentFlags().Duration("timeout", 30*time.Second, "timeout for API requests")
+	rootCmd.PersistentFlags().Int("max-retries", 3, "max retries for idempotent requests that fail or time out")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "print requests instead of sending them")
+	rootCmd.PersistentFlags().Bool("debug", false, "log outgoing requests and responses")
+
+	flagKeys := map[string]string{
+		"api-url":     "api_url",
+		"email":       "email",
+		"password":    "password",
+		"profile":     "profile",
+		"timeout":     "http_timeout",
+		"max-retries": "max_retries",
+		"dry-run":     "dry_run",
+		"debug":       "debug",
+	}
+	for name, key := range flagKeys {
+		if err := viper.BindPFlag(key, rootCmd.PersistentFlags().Lookup(name)); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -103,77 +111,95 @@ func init() {
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
-	if cfgFile != "" {
+	viper.SetConfigType("yaml")
 
+	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
+	} else if found := findConfigFile(); found != "" {
+		viper.SetConfigFile(found)
 	} else {
-		// Find home directory.
-		home, err := homedir.Dir()
+		// Nothing found anywhere in the search order; fall back to the
+		// legacy default path, which is then what viper reports as the
+		// config file in use.
+		home, err := os.UserHomeDir()
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		// Search config in home directory with name ".hovercli" (without extension).
-		viper.SetConfigName(".hovercli")
-		viper.SetConfigType("yaml")
-		viper.AddConfigPath(home)
-
+		viper.SetConfigFile(filepath.Join(home, ".hovercli.yaml"))
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	viper.SetEnvPrefix("HOVER")
+	viper.AutomaticEnv() // read in environment variables that match, e.g. HOVER_API_URL
+
+	for _, key := range []string{"api_url", "email", "password", "profile", "http_timeout", "max_retries", "spec_file", "dry_run", "debug"} {
+		if err := viper.BindEnv(key); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
 
-	// If a config file is found, read it in.
+	// If a config file is found, read it in. A missing file is fine as
+	// long as the user didn't explicitly point --config at it - HOVER_
+	// env vars and flags alone are enough to run (e.g. in CI, with no
+	// ~/.hovercli.yaml on disk). A malformed file, or an explicit
+	// --config that doesn't exist, is still fatal.
 	if err := viper.ReadInConfig(); err != nil {
-		log.Fatalln(err)
+		var notFound viper.ConfigFileNotFoundError
+		missing := errors.As(err, &notFound) || os.IsNotExist(err)
+		if !missing || cfgFile != "" {
+			log.Fatalln(err)
+		}
 	}
+
+	// Let long-lived subcommands (e.g. a streamer) pick up edited
+	// credentials without a restart. The token cache is not watched;
+	// resolveConfig re-reads it from disk on every call.
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		LogDebugf("config file changed: %s", e.Name)
+	})
+	viper.WatchConfig()
 }
 
 // Authenticate checks if a valid token exists. If the token is expired
 // then a new one is requested
 func Authenticate() error {
-	authToken := viper.GetString("auth_token")
-	authTokenExpiry := viper.GetTime("auth_token_expiry")
+	return authenticate(false)
+}
+
+// authenticate backs Authenticate. When force is true it skips the local
+// expiry check and always requests a fresh token - the 401 handler in
+// apiRequest uses this, since a 401 means the server has already revoked
+// the cached token regardless of what our local expiry says.
+func authenticate(force bool) error {
+	cfg, err := resolveConfig()
+	if err != nil {
+		return err
+	}
 
-	if authToken != "" && time.Now().Before(authTokenExpiry) {
+	if !force && cfg.AuthToken != "" && time.Now().Before(cfg.AuthTokenExpiry) {
 		return nil
 	}
-	var result map[string]string
-	email := viper.GetString("email")
-	password := viper.GetString("password")
 
+	var result map[string]string
 	requestBody, err := json.Marshal(map[string]string{
-		"email":    email,
-		"password": password,
+		"email":    cfg.Email,
+		"password": cfg.Password,
 	})
-
 	if err != nil {
 		return err
 	}
 
-	resp, err := http.Post(URL+"authenticate", "application/json", bytes.NewBuffer(requestBody))
+	resp, err := http.Post(cfg.APIURL+"authenticate", "application/json", bytes.NewBuffer(requestBody))
 	if err != nil {
 		return err
 	}
 
 	json.NewDecoder(resp.Body).Decode(&result)
-	viper.Set("auth_token", result["auth_token"])
-	viper.Set("auth_token_expiry", time.Now().Local().Add(time.Hour*2))
-	err = viper.WriteConfig()
-	return err
-
+	return writeTokenCache(tokenCache{
+		AuthToken:       result["auth_token"],
+		AuthTokenExpiry: time.Now().Local().Add(time.Hour * 2),
+	})
 }
 
-// APIRequest makes a request to the Hover API
-// The request has an Authorization header.
-func APIRequest(method string, endpoint string, payload []byte) (*http.Response, error) {
-	authToken := viper.GetString("auth_token")
-	var client http.Client
-	req, err := http.NewRequest(method, URL+endpoint, bytes.NewBuffer(payload))
-	if err != nil {
-		return &http.Response{}, err
-	}
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Authorization", authToken)
-	return client.Do(req)
-}
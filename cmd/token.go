@@ -0,0 +1,110 @@
+/*
+Copyright © 2020 Hover Developer Services <support@usehover.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// tokenCache is the whole content of the machine-managed token file. It is
+// kept separate from ~/.hovercli.yaml so that Authenticate persisting a
+// refreshed token can never clobber a user's hand-edited credentials file.
+type tokenCache struct {
+	AuthToken       string    `yaml:"auth_token"`
+	AuthTokenExpiry time.Time `yaml:"auth_token_expiry"`
+}
+
+// tokenCachePath returns where the token cache lives: $XDG_CACHE_HOME/hovercli/token.yaml
+// if XDG_CACHE_HOME is set, otherwise ~/.cache/hovercli/token.yaml.
+func tokenCachePath() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "hovercli", "token.yaml"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache", "hovercli", "token.yaml"), nil
+}
+
+// readTokenCache returns a zero-value tokenCache, with no error, if the
+// cache file doesn't exist yet - that's the normal state before the first
+// Authenticate call.
+func readTokenCache() (tokenCache, error) {
+	path, err := tokenCachePath()
+	if err != nil {
+		return tokenCache{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return tokenCache{}, nil
+	}
+	if err != nil {
+		return tokenCache{}, err
+	}
+
+	var tc tokenCache
+	if err := yaml.Unmarshal(data, &tc); err != nil {
+		return tokenCache{}, err
+	}
+	return tc, nil
+}
+
+// writeTokenCache persists tc atomically: it's written to a temp file in
+// the same directory, chmod'd to 0600, then renamed over the real path, so
+// a crash or concurrent read can never observe a partially written file.
+func writeTokenCache(tc tokenCache) error {
+	path, err := tokenCachePath()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(tc)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".token-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}